@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch"
+
+	"github.com/AmarYasser1/Book-Management-API/store"
+	"github.com/AmarYasser1/Book-Management-API/validation"
+)
+
+// patchBook handles PATCH /books/{id}, applying an RFC 7396 JSON Merge
+// Patch document to the stored book and validating the result with
+// the same schema createBook and updateBook use.
+func patchBook(w http.ResponseWriter, r *http.Request) {
+	id, err := bookIDFromVars(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	if !requireLockMatch(w, r, id) {
+		return
+	}
+
+	current, err := db.Get(id)
+	if err == store.ErrNotFound {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to load book", http.StatusInternalServerError)
+		return
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	original, err := json.Marshal(current)
+	if err != nil {
+		http.Error(w, "Failed to encode current book", http.StatusInternalServerError)
+		return
+	}
+
+	merged, err := jsonpatch.MergePatch(original, patch)
+	if err != nil {
+		http.Error(w, "Invalid merge patch document", http.StatusBadRequest)
+		return
+	}
+
+	var patched store.Book
+	if err := json.Unmarshal(merged, &patched); err != nil {
+		http.Error(w, "Patch produced an invalid book", http.StatusBadRequest)
+		return
+	}
+	patched.ID = id // the id in the path always wins, as with PUT
+
+	if problem := validation.ValidateBook(merged); problem != nil {
+		problem.WriteTo(w)
+		return
+	}
+
+	if err := db.Update(patched); err == store.ErrNotFound {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to update book", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(patched); err != nil {
+		http.Error(w, "Failed to encode updated book data", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/AmarYasser1/Book-Management-API/store"
+)
+
+const defaultLimit = 20
+
+// booksEnvelope is the response body for GET /books once pagination,
+// filtering and sorting are applied.
+type booksEnvelope struct {
+	Items  []store.Book `json:"items"`
+	Total  int          `json:"total"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+// booksQuery holds the parsed ?limit=&offset=&author=&year=&q=&sort=
+// parameters accepted by GET /books.
+type booksQuery struct {
+	limit  int
+	offset int
+
+	author  string
+	hasYear bool
+	year    int
+	q       string
+	sort    string
+}
+
+// parseBooksQuery parses and validates the supported query parameters,
+// applying defaultLimit when limit is omitted.
+func parseBooksQuery(values url.Values) (booksQuery, error) {
+	q := booksQuery{
+		limit:  defaultLimit,
+		author: values.Get("author"),
+		q:      values.Get("q"),
+		sort:   values.Get("sort"),
+	}
+
+	if v := values.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			return booksQuery{}, fmt.Errorf("invalid limit %q", v)
+		}
+		q.limit = limit
+	}
+
+	if v := values.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return booksQuery{}, fmt.Errorf("invalid offset %q", v)
+		}
+		q.offset = offset
+	}
+
+	if v := values.Get("year"); v != "" {
+		year, err := strconv.Atoi(v)
+		if err != nil {
+			return booksQuery{}, fmt.Errorf("invalid year %q", v)
+		}
+		q.hasYear = true
+		q.year = year
+	}
+
+	switch q.sort {
+	case "", "title", "year", "-year":
+	default:
+		return booksQuery{}, fmt.Errorf("invalid sort %q", q.sort)
+	}
+
+	return q, nil
+}
+
+// filterBooks returns the books matching q's author/year/q filters.
+// The q substring match is case-insensitive over title and description.
+func filterBooks(books []store.Book, q booksQuery) []store.Book {
+	filtered := make([]store.Book, 0, len(books))
+	needle := strings.ToLower(q.q)
+
+	for _, book := range books {
+		if q.author != "" && book.Author != q.author {
+			continue
+		}
+		if q.hasYear && book.PublicationYear != q.year {
+			continue
+		}
+		if needle != "" &&
+			!strings.Contains(strings.ToLower(book.Title), needle) &&
+			!strings.Contains(strings.ToLower(book.Description), needle) {
+			continue
+		}
+		filtered = append(filtered, book)
+	}
+	return filtered
+}
+
+// sortBooks stably sorts books in place by the given sort key (title,
+// year or -year for descending). An empty key leaves the order as-is.
+func sortBooks(books []store.Book, key string) {
+	switch key {
+	case "title":
+		sort.SliceStable(books, func(i, j int) bool { return books[i].Title < books[j].Title })
+	case "year":
+		sort.SliceStable(books, func(i, j int) bool { return books[i].PublicationYear < books[j].PublicationYear })
+	case "-year":
+		sort.SliceStable(books, func(i, j int) bool { return books[i].PublicationYear > books[j].PublicationYear })
+	}
+}
+
+// paginate slices books to the [offset, offset+limit) window and
+// returns that page alongside the total number of matching books. A
+// limit of 0 returns every book from offset onward.
+func paginate(books []store.Book, limit, offset int) ([]store.Book, int) {
+	total := len(books)
+
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return books[offset:end], total
+}
+
+// setPaginationLinks sets rel="next"/rel="prev" Link headers for
+// cursor-style navigation over the current filter/sort, when a further
+// or prior page exists.
+func setPaginationLinks(w http.ResponseWriter, u *url.URL, q booksQuery, total int) {
+	if q.limit <= 0 {
+		return
+	}
+
+	var links []string
+	if q.offset+q.limit < total {
+		links = append(links, linkHeader(u, q, q.offset+q.limit, "next"))
+	}
+	if q.offset > 0 {
+		prevOffset := q.offset - q.limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		links = append(links, linkHeader(u, q, prevOffset, "prev"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+func linkHeader(u *url.URL, q booksQuery, offset int, rel string) string {
+	next := *u
+	values := next.Query()
+	values.Set("limit", strconv.Itoa(q.limit))
+	values.Set("offset", strconv.Itoa(offset))
+	next.RawQuery = values.Encode()
+	return fmt.Sprintf(`<%s>; rel="%s"`, next.String(), rel)
+}
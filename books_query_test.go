@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/AmarYasser1/Book-Management-API/store"
+)
+
+func TestParseBooksQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{name: "empty", query: "", wantErr: false},
+		{name: "valid limit and offset", query: "limit=5&offset=10", wantErr: false},
+		{name: "valid sort", query: "sort=-year", wantErr: false},
+		{name: "invalid sort", query: "sort=bogus", wantErr: true},
+		{name: "negative limit", query: "limit=-1", wantErr: true},
+		{name: "non-numeric year", query: "year=abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("url.ParseQuery(%q) failed: %v", tt.query, err)
+			}
+
+			_, err = parseBooksQuery(values)
+			if tt.wantErr && err == nil {
+				t.Fatalf("parseBooksQuery(%q) = nil error, want one", tt.query)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("parseBooksQuery(%q) = %v, want nil error", tt.query, err)
+			}
+		})
+	}
+}
+
+func TestPaginateIsStableOverRepeatedCalls(t *testing.T) {
+	books := []store.Book{
+		{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5},
+	}
+
+	firstPage, total := paginate(books, 2, 0)
+	if total != 5 {
+		t.Fatalf("total = %d, want 5", total)
+	}
+	wantFirst := []int{1, 2}
+	for i, want := range wantFirst {
+		if firstPage[i].ID != want {
+			t.Fatalf("firstPage[%d].ID = %d, want %d", i, firstPage[i].ID, want)
+		}
+	}
+
+	// A second call over the same, unmodified slice must return the
+	// same page: pagination cursors are only meaningful if GetAll (and
+	// anything upstream of paginate) returns books in a stable order.
+	secondPage, _ := paginate(books, 2, 2)
+	wantSecond := []int{3, 4}
+	for i, want := range wantSecond {
+		if secondPage[i].ID != want {
+			t.Fatalf("secondPage[%d].ID = %d, want %d", i, secondPage[i].ID, want)
+		}
+	}
+}
+
+func TestPaginateLimitZeroReturnsRemainder(t *testing.T) {
+	books := []store.Book{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	page, total := paginate(books, 0, 1)
+	if total != 3 {
+		t.Fatalf("total = %d, want 3", total)
+	}
+	if len(page) != 2 || page[0].ID != 2 || page[1].ID != 3 {
+		t.Fatalf("page = %+v, want books 2 and 3", page)
+	}
+}
+
+func TestFilterBooks(t *testing.T) {
+	books := []store.Book{
+		{ID: 1, Title: "Dune", Author: "Frank Herbert", PublicationYear: 1965},
+		{ID: 2, Title: "Foundation", Author: "Isaac Asimov", PublicationYear: 1951},
+		{ID: 3, Title: "Duel", Author: "Frank Herbert", PublicationYear: 1970},
+	}
+
+	filtered := filterBooks(books, booksQuery{author: "Frank Herbert"})
+	if len(filtered) != 2 {
+		t.Fatalf("filterBooks by author = %d books, want 2", len(filtered))
+	}
+
+	filtered = filterBooks(books, booksQuery{q: "du"})
+	if len(filtered) != 2 {
+		t.Fatalf("filterBooks by q=du = %d books, want 2", len(filtered))
+	}
+
+	filtered = filterBooks(books, booksQuery{hasYear: true, year: 1951})
+	if len(filtered) != 1 || filtered[0].ID != 2 {
+		t.Fatalf("filterBooks by year = %+v, want only book 2", filtered)
+	}
+}
+
+func TestSortBooksDefaultLeavesOrderAsIs(t *testing.T) {
+	books := []store.Book{{ID: 3}, {ID: 1}, {ID: 2}}
+	sortBooks(books, "")
+
+	want := []int{3, 1, 2}
+	for i, id := range want {
+		if books[i].ID != id {
+			t.Fatalf("sortBooks with no key reordered input: got %+v, want ids in order %v", books, want)
+		}
+	}
+}
+
+func TestSortBooksByYear(t *testing.T) {
+	books := []store.Book{
+		{ID: 1, PublicationYear: 1970},
+		{ID: 2, PublicationYear: 1951},
+		{ID: 3, PublicationYear: 1965},
+	}
+
+	sortBooks(books, "year")
+	want := []int{2, 3, 1}
+	for i, id := range want {
+		if books[i].ID != id {
+			t.Fatalf("sortBooks(year) = %+v, want ids in order %v", books, want)
+		}
+	}
+
+	sortBooks(books, "-year")
+	want = []int{1, 3, 2}
+	for i, id := range want {
+		if books[i].ID != id {
+			t.Fatalf("sortBooks(-year) = %+v, want ids in order %v", books, want)
+		}
+	}
+}
@@ -2,117 +2,213 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
-	"strings"
-	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/AmarYasser1/Book-Management-API/middleware"
+	"github.com/AmarYasser1/Book-Management-API/store"
+	"github.com/AmarYasser1/Book-Management-API/store/factory"
+	_ "github.com/AmarYasser1/Book-Management-API/store/memory"
+	_ "github.com/AmarYasser1/Book-Management-API/store/sqlite"
+	"github.com/AmarYasser1/Book-Management-API/validation"
 )
 
-type Book struct {
-	ID              int    `json:"id"`
-	Title           string `json:"title"`
-	Description     string `json:"description"`
-	Author          string `json:"author"`
-	PublicationYear int    `json:"publication_year"`
-}
+const (
+	defaultStore = "memory"
 
-var (
-	books = make(map[int]Book)
-	mtx   = &sync.Mutex{}
+	// lockReapInterval is how often the background goroutine checks for
+	// locks whose TTL has elapsed.
+	lockReapInterval = 30 * time.Second
 )
 
+var db store.Store
+
 func main() {
-	http.HandleFunc("/books", getAllBooks)
-
-	http.HandleFunc("/books/", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case "GET":
-			getBookById(w, r)
-		case "POST":
-			createBook(w, r)
-		case "PUT":
-			updateBook(w, r)
-		case "DELETE":
-			deleteBook(w, r)
-		default:
-			http.Error(w, "Unsupported method", http.StatusMethodNotAllowed)
+	storeName := flag.String("store", storeNameFromEnv(), "storage backend to use (memory|sqlite)")
+	metricsAddr := flag.String("metrics-addr", "", "address to serve Prometheus metrics on (empty disables a separate metrics server)")
+	flag.Parse()
+
+	s, err := factory.New(*storeName)
+	if err != nil {
+		log.Fatalf("store: %v", err)
+	}
+	db = s
+	updateBooksTotalGauge(db)
+
+	go reapStaleLocksPeriodically(db, lockReapInterval)
+
+	r := mux.NewRouter()
+	r.Use(middleware.Logging)
+	r.Use(middleware.Metrics)
+	r.HandleFunc("/books", getAllBooks).Methods(http.MethodGet)
+	r.HandleFunc("/books", createBook).Methods(http.MethodPost)
+	r.HandleFunc("/books/schema", getBookSchema).Methods(http.MethodGet)
+	r.HandleFunc("/books/{id:[0-9]+}", getBookById).Methods(http.MethodGet)
+	r.HandleFunc("/books/{id:[0-9]+}", updateBook).Methods(http.MethodPut)
+	r.HandleFunc("/books/{id:[0-9]+}", patchBook).Methods(http.MethodPatch)
+	r.HandleFunc("/books/{id:[0-9]+}", deleteBook).Methods(http.MethodDelete)
+	r.HandleFunc("/books/{id:[0-9]+}", lockBook).Methods("LOCK")
+	r.HandleFunc("/books/{id:[0-9]+}", unlockBook).Methods("UNLOCK")
+
+	if *metricsAddr != "" {
+		go serveMetrics(*metricsAddr)
+	} else {
+		r.Handle("/metrics", promhttp.Handler())
+	}
+
+	fmt.Printf("Server is running on http://localhost:8080 (store=%s)\n", *storeName)
+	log.Fatal(http.ListenAndServe(":8080", r))
+}
+
+// serveMetrics runs a dedicated HTTP server exposing /metrics, so
+// scraping can happen on a port separate from the book API.
+func serveMetrics(addr string) {
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("Metrics are running on http://%s/metrics", addr)
+	log.Fatal(http.ListenAndServe(addr, metricsMux))
+}
+
+// updateBooksTotalGauge refreshes the books_total gauge from the
+// store's current contents. Call it after every mutation.
+func updateBooksTotalGauge(db store.Store) {
+	books, err := db.GetAll()
+	if err != nil {
+		log.Printf("books_total: %v", err)
+		return
+	}
+	middleware.BooksTotal.Set(float64(len(books)))
+}
+
+// reapStaleLocksPeriodically releases expired locks until the process
+// exits. It runs in its own goroutine so writers are never blocked on
+// reaping.
+func reapStaleLocksPeriodically(db store.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if n, err := db.ReapStaleLocks(); err != nil {
+			log.Printf("lock reaper: %v", err)
+		} else if n > 0 {
+			log.Printf("lock reaper: released %d stale lock(s)", n)
 		}
-	})
+	}
+}
 
-	fmt.Println("Server is running on http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+// storeNameFromEnv returns BOOKS_STORE if set, otherwise the default
+// backend. It only supplies the flag's default, so an explicit --store
+// still wins.
+func storeNameFromEnv() string {
+	if name := os.Getenv("BOOKS_STORE"); name != "" {
+		return name
+	}
+	return defaultStore
 }
 
 // CRUDs operations
 func getAllBooks(w http.ResponseWriter, r *http.Request) {
-	mtx.Lock()
-	defer mtx.Unlock()
+	books, err := db.GetAll()
+	if err != nil {
+		http.Error(w, "Failed to load books", http.StatusInternalServerError)
+		return
+	}
 
-	// Send response
-	w.Header().Set("Content-Type", "application/json")
+	q, err := parseBooksQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filtered := filterBooks(books, q)
+	sortBooks(filtered, q.sort)
+
+	page, total := paginate(filtered, q.limit, q.offset)
+	setPaginationLinks(w, r.URL, q, total)
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
-	err := json.NewEncoder(w).Encode(books) // Convert from book as a map to json
-	if err != nil {
+	env := booksEnvelope{Items: page, Total: total, Limit: q.limit, Offset: q.offset}
+	if err := json.NewEncoder(w).Encode(env); err != nil {
 		http.Error(w, "Failed to encode books data", http.StatusInternalServerError)
 		return
 	}
 }
 
 func getBookById(w http.ResponseWriter, r *http.Request) {
-	mtx.Lock()
-	defer mtx.Unlock()
-
-	idStr := strings.TrimPrefix(r.URL.Path, "/books/")
-
-	id, err := strconv.Atoi(idStr) // Convet from string to int
+	id, err := bookIDFromVars(r)
 	if err != nil {
 		http.Error(w, "Invalid book ID", http.StatusBadRequest)
 		return
 	}
 
-	book, isExist := books[id]
-	if !isExist {
+	book, err := db.Get(id)
+	if err == store.ErrNotFound {
 		http.Error(w, "Book not found", http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		http.Error(w, "Failed to load book", http.StatusInternalServerError)
+		return
+	}
 
 	// Send response
 	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(book)
-	if err != nil {
+	if err := json.NewEncoder(w).Encode(book); err != nil {
 		http.Error(w, "Failed to encode book data", http.StatusInternalServerError)
 		return
 	}
 }
 
 func createBook(w http.ResponseWriter, r *http.Request) {
-	mtx.Lock()
-	defer mtx.Unlock()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
 
-	var newBook Book
-	if err := json.NewDecoder(r.Body).Decode(&newBook); err != nil {
+	var newBook store.Book
+	if err := json.Unmarshal(body, &newBook); err != nil {
 		http.Error(w, "Invalid input", http.StatusBadRequest)
 		return
 	}
 
-	// Check if didn't fill the id
-	if newBook.ID == 0 {
-		http.Error(w, "Book id is required", http.StatusBadRequest)
+	if problem := validation.ValidateBook(body); problem != nil {
+		problem.WriteTo(w)
 		return
 	}
 
-	// Check if this id already exist
-	if _, exists := books[newBook.ID]; exists {
+	// A client that didn't supply an id gets one assigned atomically by
+	// the store, so two concurrent id-less POSTs can't collide.
+	if newBook.ID == 0 {
+		id, err := db.CreateAuto(newBook)
+		if err != nil {
+			http.Error(w, "Failed to create book", http.StatusInternalServerError)
+			return
+		}
+		newBook.ID = id
+	} else if err := db.Create(newBook); err == store.ErrAlreadyExists {
 		http.Error(w, "Book already exists", http.StatusConflict)
 		return
+	} else if err != nil {
+		http.Error(w, "Failed to create book", http.StatusInternalServerError)
+		return
 	}
+	updateBooksTotalGauge(db)
 
-	books[newBook.ID] = newBook
-
+	w.Header().Set("Location", fmt.Sprintf("/books/%d", newBook.ID))
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(newBook); err != nil {
 		http.Error(w, "Failed to encode book data", http.StatusInternalServerError)
@@ -120,27 +216,30 @@ func createBook(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func updateBook(w http.ResponseWriter, r *http.Request) {
-	mtx.Lock()
-	defer mtx.Unlock()
-
-	idStr := strings.TrimPrefix(r.URL.Path, "/books/")
+// getBookSchema handles GET /books/schema, exposing the JSON Schema
+// that createBook, updateBook and patchBook validate against so
+// clients can pre-validate before sending a request.
+func getBookSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(validation.BookSchema())
+}
 
-	id, err := strconv.Atoi(idStr)
+func updateBook(w http.ResponseWriter, r *http.Request) {
+	id, err := bookIDFromVars(r)
 	if err != nil {
 		http.Error(w, "Invalid book ID", http.StatusBadRequest)
 		return
 	}
 
-	// Ensure that the book exists
-	if _, exists := books[id]; !exists {
-		http.Error(w, "Book not found", http.StatusNotFound)
+	// Update the specified book
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
 
-	// Update the specified book
-	var updatedBook Book
-	if err := json.NewDecoder(r.Body).Decode(&updatedBook); err != nil {
+	var updatedBook store.Book
+	if err := json.Unmarshal(body, &updatedBook); err != nil {
 		http.Error(w, "Invalid input", http.StatusBadRequest)
 		return
 	}
@@ -150,7 +249,22 @@ func updateBook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	books[id] = updatedBook
+	if !requireLockMatch(w, r, id) {
+		return
+	}
+
+	if problem := validation.ValidateBook(body); problem != nil {
+		problem.WriteTo(w)
+		return
+	}
+
+	if err := db.Update(updatedBook); err == store.ErrNotFound {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to update book", http.StatusInternalServerError)
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(updatedBook); err != nil {
@@ -160,24 +274,133 @@ func updateBook(w http.ResponseWriter, r *http.Request) {
 }
 
 func deleteBook(w http.ResponseWriter, r *http.Request) {
-	mtx.Lock()
-	defer mtx.Unlock()
+	id, err := bookIDFromVars(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		return
+	}
+
+	if !requireLockMatch(w, r, id) {
+		return
+	}
+
+	if err := db.Delete(id); err == store.ErrNotFound {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to delete book", http.StatusInternalServerError)
+		return
+	}
+	updateBooksTotalGauge(db)
 
-	idStr := strings.TrimPrefix(r.URL.Path, "/books/")
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	id, err := strconv.Atoi(idStr)
+// requireLockMatch reports whether a write to book id may proceed: true
+// if the book is unlocked, or locked with an X-Lock-ID header that
+// matches. Otherwise it writes the appropriate error response itself
+// (404 if the book doesn't exist, 409 with the held lock otherwise) and
+// returns false.
+func requireLockMatch(w http.ResponseWriter, r *http.Request, id int) bool {
+	lock, held, err := db.GetLock(id)
+	if err != nil {
+		http.Error(w, "Failed to check lock", http.StatusInternalServerError)
+		return false
+	}
+	if !held {
+		return true
+	}
+	if r.Header.Get("X-Lock-ID") == lock.ID {
+		return true
+	}
+
+	middleware.BooksLockWaitSeconds.Set(time.Since(lock.Created).Seconds())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(lock)
+	return false
+}
+
+// lockBook handles LOCK /books/{id}, acquiring an advisory lock on the
+// book modeled on Terraform's remote state locking protocol.
+func lockBook(w http.ResponseWriter, r *http.Request) {
+	id, err := bookIDFromVars(r)
 	if err != nil {
 		http.Error(w, "Invalid book ID", http.StatusBadRequest)
 		return
 	}
 
-	// Ensure that the book exists
-	if _, exists := books[id]; !exists {
+	var lock store.Lock
+	if err := json.NewDecoder(r.Body).Decode(&lock); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+	if lock.ID == "" {
+		http.Error(w, "Lock ID is required", http.StatusBadRequest)
+		return
+	}
+	if lock.Created.IsZero() {
+		lock.Created = time.Now()
+	}
+
+	err = db.Lock(id, lock)
+	switch {
+	case err == nil:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(lock)
+	case err == store.ErrNotFound:
 		http.Error(w, "Book not found", http.StatusNotFound)
+	default:
+		var heldErr *store.LockHeldError
+		if errors.As(err, &heldErr) {
+			middleware.BooksLockWaitSeconds.Set(time.Since(heldErr.Lock.Created).Seconds())
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(heldErr.Lock)
+			return
+		}
+		http.Error(w, "Failed to acquire lock", http.StatusInternalServerError)
+	}
+}
+
+// unlockBook handles UNLOCK /books/{id}, releasing a previously
+// acquired lock when the request body's ID matches the held lock.
+func unlockBook(w http.ResponseWriter, r *http.Request) {
+	id, err := bookIDFromVars(r)
+	if err != nil {
+		http.Error(w, "Invalid book ID", http.StatusBadRequest)
 		return
 	}
 
-	delete(books, id)
+	var req store.Lock
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
 
-	w.WriteHeader(http.StatusNoContent)
+	err = db.Unlock(id, req.ID)
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusOK)
+	case err == store.ErrNotFound:
+		http.Error(w, "Book not found", http.StatusNotFound)
+	case err == store.ErrNotLocked:
+		if lock, held, getErr := db.GetLock(id); getErr == nil && held {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(lock)
+			return
+		}
+		http.Error(w, "Book is not locked", http.StatusConflict)
+	default:
+		http.Error(w, "Failed to release lock", http.StatusInternalServerError)
+	}
+}
+
+// bookIDFromVars parses the {id} path variable that mux extracted for
+// this request. The route pattern already restricts it to digits, so
+// this only fails if the handler is wired to a route without that var.
+func bookIDFromVars(r *http.Request) (int, error) {
+	return strconv.Atoi(mux.Vars(r)["id"])
 }
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Logging wraps next, emitting a structured slog record for every
+// request: method, path, status, duration and (when the route has an
+// {id} path variable) the book id.
+func Logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(sw, r)
+
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.status,
+			"duration", time.Since(start),
+		}
+		if id := mux.Vars(r)["id"]; id != "" {
+			attrs = append(attrs, "book_id", id)
+		}
+		slog.Info("http request", attrs...)
+	})
+}
@@ -0,0 +1,85 @@
+// Package middleware provides cross-cutting HTTP middleware (request
+// logging, Prometheus instrumentation) shared by every handler.
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// RequestsTotal counts every handled request by method, route
+	// pattern and response status.
+	RequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests processed.",
+	}, []string{"method", "path", "status"})
+
+	// RequestDuration tracks handler latency by method, route pattern
+	// and response status.
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request handling duration in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+
+	// BooksTotal reports the current number of stored books.
+	BooksTotal = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "books_total",
+		Help: "Current number of books in the store.",
+	})
+
+	// BooksLockWaitSeconds reports how long the most recent rejected
+	// write was blocked behind an existing advisory lock.
+	BooksLockWaitSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "books_lock_wait_seconds",
+		Help: "Age of the advisory lock that most recently blocked a write.",
+	})
+)
+
+// statusWriter records the status code written through an
+// http.ResponseWriter so middleware can observe it after the handler
+// returns.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Metrics wraps next, recording RequestsTotal and RequestDuration for
+// every request. It must run as mux router middleware (via r.Use) so
+// that mux.CurrentRoute can supply the matched route's path template
+// (e.g. "/books/{id}") instead of the literal path, keeping label
+// cardinality bounded.
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(sw, r)
+
+		status := strconv.Itoa(sw.status)
+		RequestsTotal.WithLabelValues(r.Method, routePattern(r), status).Inc()
+		RequestDuration.WithLabelValues(r.Method, routePattern(r), status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// routePattern returns the path template of the route mux matched for
+// r, falling back to the literal path if none matched (e.g. a 404).
+func routePattern(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tpl, err := route.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return r.URL.Path
+}
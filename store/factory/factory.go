@@ -0,0 +1,48 @@
+// Package factory is a registry of named store.Store backends. Backend
+// packages register themselves from an init() function so that main can
+// select one by name (e.g. from a flag or environment variable) without
+// importing backend packages directly.
+package factory
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/AmarYasser1/Book-Management-API/store"
+)
+
+// Constructor builds a backend on demand. It is only called for the
+// backend name actually selected, so a backend with side effects (e.g.
+// opening a file) never runs unless it's chosen.
+type Constructor func() (store.Store, error)
+
+var (
+	mtx      sync.Mutex
+	backends = make(map[string]Constructor)
+)
+
+// Register makes a backend available under name, built lazily by new
+// when first selected. It panics if name is already registered,
+// mirroring the database/sql driver pattern.
+func Register(name string, new Constructor) {
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if _, exists := backends[name]; exists {
+		panic(fmt.Sprintf("store/factory: backend %q already registered", name))
+	}
+	backends[name] = new
+}
+
+// New builds and returns the backend registered under name, or an
+// error if none has been registered or construction fails.
+func New(name string) (store.Store, error) {
+	mtx.Lock()
+	new, exists := backends[name]
+	mtx.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("store/factory: no backend registered under %q", name)
+	}
+	return new()
+}
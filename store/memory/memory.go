@@ -0,0 +1,159 @@
+// Package memory implements store.Store with a process-local map. It
+// preserves the behavior of the original in-memory implementation and
+// is the default backend.
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AmarYasser1/Book-Management-API/store"
+	"github.com/AmarYasser1/Book-Management-API/store/factory"
+)
+
+func init() {
+	factory.Register("memory", func() (store.Store, error) { return New(), nil })
+}
+
+// Store is an in-memory, mutex-guarded store.Store implementation.
+type Store struct {
+	mtx   sync.Mutex
+	books map[int]store.Book
+	locks map[int]store.Lock
+}
+
+// New returns an empty in-memory store.
+func New() *Store {
+	return &Store{
+		books: make(map[int]store.Book),
+		locks: make(map[int]store.Lock),
+	}
+}
+
+func (s *Store) Create(book store.Book) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, exists := s.books[book.ID]; exists {
+		return store.ErrAlreadyExists
+	}
+	s.books[book.ID] = book
+	return nil
+}
+
+func (s *Store) CreateAuto(book store.Book) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	max := 0
+	for id := range s.books {
+		if id > max {
+			max = id
+		}
+	}
+
+	book.ID = max + 1
+	s.books[book.ID] = book
+	return book.ID, nil
+}
+
+func (s *Store) Update(book store.Book) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, exists := s.books[book.ID]; !exists {
+		return store.ErrNotFound
+	}
+	s.books[book.ID] = book
+	return nil
+}
+
+func (s *Store) Get(id int) (store.Book, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	book, exists := s.books[id]
+	if !exists {
+		return store.Book{}, store.ErrNotFound
+	}
+	return book, nil
+}
+
+func (s *Store) GetAll() ([]store.Book, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	all := make([]store.Book, 0, len(s.books))
+	for _, book := range s.books {
+		all = append(all, book)
+	}
+	// Map iteration order is randomized; sort by id so repeated calls
+	// (and the pagination cursors derived from them) see a stable order.
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all, nil
+}
+
+func (s *Store) Delete(id int) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, exists := s.books[id]; !exists {
+		return store.ErrNotFound
+	}
+	delete(s.books, id)
+	delete(s.locks, id)
+	return nil
+}
+
+func (s *Store) Lock(bookID int, lock store.Lock) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, exists := s.books[bookID]; !exists {
+		return store.ErrNotFound
+	}
+	if existing, held := s.locks[bookID]; held {
+		return &store.LockHeldError{Lock: existing}
+	}
+	s.locks[bookID] = lock
+	return nil
+}
+
+func (s *Store) Unlock(bookID int, lockID string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, exists := s.books[bookID]; !exists {
+		return store.ErrNotFound
+	}
+	existing, held := s.locks[bookID]
+	if !held || existing.ID != lockID {
+		return store.ErrNotLocked
+	}
+	delete(s.locks, bookID)
+	return nil
+}
+
+func (s *Store) GetLock(bookID int) (store.Lock, bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	lock, held := s.locks[bookID]
+	return lock, held, nil
+}
+
+func (s *Store) ReapStaleLocks() (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	now := time.Now()
+	reaped := 0
+	for id, lock := range s.locks {
+		if lock.TTL > 0 && now.After(lock.Created.Add(lock.TTL)) {
+			delete(s.locks, id)
+			reaped++
+		}
+	}
+	return reaped, nil
+}
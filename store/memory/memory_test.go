@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/AmarYasser1/Book-Management-API/store"
+)
+
+func TestGetAllIsSortedByID(t *testing.T) {
+	s := New()
+	for _, id := range []int{5, 3, 1, 4, 2} {
+		if err := s.Create(store.Book{ID: id}); err != nil {
+			t.Fatalf("Create(%d) failed: %v", id, err)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		all, err := s.GetAll()
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		for j, book := range all {
+			if book.ID != j+1 {
+				t.Fatalf("GetAll()[%d].ID = %d, want %d (call %d)", j, book.ID, j+1, i)
+			}
+		}
+	}
+}
+
+func TestCreateAutoAssignsDistinctIDsUnderConcurrency(t *testing.T) {
+	s := New()
+
+	const n = 50
+	ids := make([]int, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id, err := s.CreateAuto(store.Book{Title: "concurrent"})
+			ids[i] = id
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool, n)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("CreateAuto call %d failed: %v", i, err)
+		}
+		if seen[ids[i]] {
+			t.Fatalf("CreateAuto assigned id %d more than once", ids[i])
+		}
+		seen[ids[i]] = true
+	}
+
+	all, err := s.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if len(all) != n {
+		t.Fatalf("len(GetAll()) = %d, want %d", len(all), n)
+	}
+}
@@ -0,0 +1,269 @@
+// Package sqlite implements store.Store on top of a SQLite database via
+// database/sql, for deployments that need books to survive a restart.
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/AmarYasser1/Book-Management-API/store"
+	"github.com/AmarYasser1/Book-Management-API/store/factory"
+)
+
+const defaultDSN = "books.db"
+
+func init() {
+	// Registration only records the constructor; Open (which creates
+	// books.db and its schema on disk) doesn't run unless "sqlite" is
+	// actually selected as the backend.
+	factory.Register("sqlite", func() (store.Store, error) {
+		dsn := os.Getenv("BOOKS_SQLITE_DSN")
+		if dsn == "" {
+			dsn = defaultDSN
+		}
+		return Open(dsn)
+	})
+}
+
+// Store is a database/sql-backed store.Store implementation.
+type Store struct {
+	db *sql.DB
+
+	// autoMtx serializes CreateAuto so that reading the current max id
+	// and inserting under it happens atomically across goroutines.
+	autoMtx sync.Mutex
+}
+
+// Open opens (creating if necessary) the SQLite database at dsn and
+// ensures the books table exists.
+func Open(dsn string) (*Store, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: open %s: %w", dsn, err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS books (
+	id               INTEGER PRIMARY KEY,
+	title            TEXT NOT NULL,
+	description      TEXT,
+	author           TEXT NOT NULL,
+	publication_year INTEGER
+);
+CREATE TABLE IF NOT EXISTS book_locks (
+	book_id   INTEGER PRIMARY KEY REFERENCES books(id) ON DELETE CASCADE,
+	id        TEXT NOT NULL,
+	operation TEXT,
+	who       TEXT,
+	created   DATETIME NOT NULL,
+	info      TEXT,
+	ttl_ns    INTEGER
+);`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite: create schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Create(book store.Book) error {
+	_, err := s.db.Exec(
+		`INSERT INTO books (id, title, description, author, publication_year) VALUES (?, ?, ?, ?, ?)`,
+		book.ID, book.Title, book.Description, book.Author, book.PublicationYear,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return store.ErrAlreadyExists
+		}
+		return fmt.Errorf("sqlite: create: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) CreateAuto(book store.Book) (int, error) {
+	s.autoMtx.Lock()
+	defer s.autoMtx.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: create auto: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	var max int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(id), 0) FROM books`).Scan(&max); err != nil {
+		return 0, fmt.Errorf("sqlite: create auto: max id: %w", err)
+	}
+	book.ID = max + 1
+
+	_, err = tx.Exec(
+		`INSERT INTO books (id, title, description, author, publication_year) VALUES (?, ?, ?, ?, ?)`,
+		book.ID, book.Title, book.Description, book.Author, book.PublicationYear,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: create auto: insert: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("sqlite: create auto: commit: %w", err)
+	}
+	return book.ID, nil
+}
+
+func (s *Store) Update(book store.Book) error {
+	res, err := s.db.Exec(
+		`UPDATE books SET title = ?, description = ?, author = ?, publication_year = ? WHERE id = ?`,
+		book.Title, book.Description, book.Author, book.PublicationYear, book.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("sqlite: update: %w", err)
+	}
+	return requireRowAffected(res)
+}
+
+func (s *Store) Get(id int) (store.Book, error) {
+	row := s.db.QueryRow(
+		`SELECT id, title, description, author, publication_year FROM books WHERE id = ?`, id,
+	)
+
+	var book store.Book
+	err := row.Scan(&book.ID, &book.Title, &book.Description, &book.Author, &book.PublicationYear)
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.Book{}, store.ErrNotFound
+	}
+	if err != nil {
+		return store.Book{}, fmt.Errorf("sqlite: get: %w", err)
+	}
+	return book, nil
+}
+
+func (s *Store) GetAll() ([]store.Book, error) {
+	rows, err := s.db.Query(`SELECT id, title, description, author, publication_year FROM books ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite: get all: %w", err)
+	}
+	defer rows.Close()
+
+	var all []store.Book
+	for rows.Next() {
+		var book store.Book
+		if err := rows.Scan(&book.ID, &book.Title, &book.Description, &book.Author, &book.PublicationYear); err != nil {
+			return nil, fmt.Errorf("sqlite: scan: %w", err)
+		}
+		all = append(all, book)
+	}
+	return all, rows.Err()
+}
+
+func (s *Store) Delete(id int) error {
+	res, err := s.db.Exec(`DELETE FROM books WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("sqlite: delete: %w", err)
+	}
+	return requireRowAffected(res)
+}
+
+func (s *Store) Lock(bookID int, lock store.Lock) error {
+	if _, err := s.Get(bookID); err != nil {
+		return err
+	}
+
+	if existing, held, err := s.GetLock(bookID); err != nil {
+		return err
+	} else if held {
+		return &store.LockHeldError{Lock: existing}
+	}
+
+	_, err := s.db.Exec(
+		`INSERT INTO book_locks (book_id, id, operation, who, created, info, ttl_ns) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		bookID, lock.ID, lock.Operation, lock.Who, lock.Created, lock.Info, lock.TTL,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			// Lost the race to acquire; report whoever won.
+			existing, held, getErr := s.GetLock(bookID)
+			if getErr == nil && held {
+				return &store.LockHeldError{Lock: existing}
+			}
+		}
+		return fmt.Errorf("sqlite: lock: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) Unlock(bookID int, lockID string) error {
+	if _, err := s.Get(bookID); err != nil {
+		return err
+	}
+
+	res, err := s.db.Exec(`DELETE FROM book_locks WHERE book_id = ? AND id = ?`, bookID, lockID)
+	if err != nil {
+		return fmt.Errorf("sqlite: unlock: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sqlite: rows affected: %w", err)
+	}
+	if n == 0 {
+		return store.ErrNotLocked
+	}
+	return nil
+}
+
+func (s *Store) GetLock(bookID int) (store.Lock, bool, error) {
+	row := s.db.QueryRow(
+		`SELECT id, operation, who, created, info, ttl_ns FROM book_locks WHERE book_id = ?`, bookID,
+	)
+
+	var lock store.Lock
+	err := row.Scan(&lock.ID, &lock.Operation, &lock.Who, &lock.Created, &lock.Info, &lock.TTL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.Lock{}, false, nil
+	}
+	if err != nil {
+		return store.Lock{}, false, fmt.Errorf("sqlite: get lock: %w", err)
+	}
+	return lock, true, nil
+}
+
+func (s *Store) ReapStaleLocks() (int, error) {
+	res, err := s.db.Exec(
+		`DELETE FROM book_locks WHERE ttl_ns > 0 AND datetime(created, '+' || (ttl_ns / 1000000000) || ' seconds') < ?`,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: reap stale locks: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+func requireRowAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("sqlite: rows affected: %w", err)
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unique") || strings.Contains(msg, "constraint")
+}
@@ -0,0 +1,103 @@
+// Package store defines the storage abstraction used by the book API
+// handlers, along with the shared Book type that every backend persists.
+package store
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get, Update and Delete when no book exists
+// with the given id.
+var ErrNotFound = errors.New("store: book not found")
+
+// ErrAlreadyExists is returned by Create when a book with the given id
+// is already present.
+var ErrAlreadyExists = errors.New("store: book already exists")
+
+// ErrNotLocked is returned by Unlock when the book has no lock held.
+var ErrNotLocked = errors.New("store: book not locked")
+
+// Book is the persisted representation of a library entry.
+type Book struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Author      string `json:"author"`
+
+	// PublicationYear is optional; omitempty keeps a book that was
+	// created without one round-tripping (e.g. through PATCH) as
+	// "no year" rather than the ambiguous literal 0.
+	PublicationYear int `json:"publication_year,omitempty"`
+}
+
+// Lock is an advisory, Terraform-style lock held on a single book. A
+// held Lock blocks writes from any caller that doesn't present its ID.
+type Lock struct {
+	ID        string    `json:"ID"`
+	Operation string    `json:"Operation"`
+	Who       string    `json:"Who"`
+	Created   time.Time `json:"Created"`
+	Info      string    `json:"Info,omitempty"`
+
+	// TTL, if non-zero, marks the lock stale once Created+TTL has
+	// passed, letting background reaping release it automatically.
+	TTL time.Duration `json:"TTL,omitempty"`
+}
+
+// LockHeldError is returned by Lock when the book is already locked by
+// someone else. It carries the existing lock so the caller can surface
+// it to the client (e.g. as the body of a 409 response).
+type LockHeldError struct {
+	Lock Lock
+}
+
+func (e *LockHeldError) Error() string {
+	return "store: book already locked by " + e.Lock.Who
+}
+
+// Store is implemented by every storage backend the API can run against.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Create persists a new book. It returns ErrAlreadyExists if a book
+	// with the same id is already stored.
+	Create(book Book) error
+
+	// CreateAuto persists book under an id one greater than the current
+	// maximum, chosen and inserted atomically so that concurrent calls
+	// never collide, and returns the assigned id.
+	CreateAuto(book Book) (id int, err error)
+
+	// Update replaces the book stored under book.ID. It returns
+	// ErrNotFound if no such book exists.
+	Update(book Book) error
+
+	// Get returns the book stored under id, or ErrNotFound.
+	Get(id int) (Book, error)
+
+	// GetAll returns every stored book in ascending id order, so callers
+	// that paginate over it see a stable order across calls.
+	GetAll() ([]Book, error)
+
+	// Delete removes the book stored under id, or returns ErrNotFound.
+	Delete(id int) error
+
+	// Lock acquires an advisory lock on bookID. It returns
+	// *LockHeldError if the book is already locked, or ErrNotFound if
+	// the book doesn't exist.
+	Lock(bookID int, lock Lock) error
+
+	// Unlock releases the lock on bookID if lockID matches the held
+	// lock's ID. It returns ErrNotFound if the book doesn't exist and
+	// ErrNotLocked if the held lock's ID doesn't match (or no lock is
+	// held).
+	Unlock(bookID int, lockID string) error
+
+	// GetLock returns the lock currently held on bookID, if any.
+	GetLock(bookID int) (lock Lock, held bool, err error)
+
+	// ReapStaleLocks releases every lock whose TTL has elapsed and
+	// returns how many were released. It is safe to call periodically
+	// from a background goroutine.
+	ReapStaleLocks() (int, error)
+}
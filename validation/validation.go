@@ -0,0 +1,111 @@
+// Package validation validates Book payloads against a JSON Schema and
+// reports failures as RFC 7807 problem details, so API clients get a
+// field-by-field explanation instead of a single generic message.
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var schema *gojsonschema.Schema
+
+func init() {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(BookSchema()))
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid book schema: %v", err))
+	}
+	schema = s
+}
+
+// BookSchema returns the JSON Schema that createBook, updateBook and
+// patchBook validate against. The publication_year upper bound tracks
+// the current year, so it's generated rather than a static constant.
+func BookSchema() []byte {
+	maxYear := time.Now().Year() + 1
+	return []byte(fmt.Sprintf(`{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"title": "Book",
+	"type": "object",
+	"required": ["title", "author"],
+	"properties": {
+		"title": {
+			"type": "string",
+			"minLength": 1,
+			"maxLength": 500
+		},
+		"author": {
+			"type": "string",
+			"minLength": 1
+		},
+		"description": {
+			"type": "string",
+			"maxLength": 5000
+		},
+		"publication_year": {
+			"type": "integer",
+			"minimum": 1000,
+			"maximum": %d
+		}
+	}
+}`, maxYear))
+}
+
+// FieldError is a single field-level validation failure reported
+// inside a Problem's Errors slice.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Problem is an RFC 7807 application/problem+json response body.
+type Problem struct {
+	Type   string       `json:"type,omitempty"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// WriteTo encodes p as application/problem+json to w, setting the
+// response status to p.Status.
+func (p *Problem) WriteTo(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// ValidateBook validates the raw decoded JSON body of a book request
+// against BookSchema and returns nil if it passes. Otherwise it
+// returns a Problem listing every offending field.
+//
+// It takes raw JSON rather than a store.Book so that a field the
+// client omitted (e.g. publication_year) is seen as absent, not as
+// the Go zero value — re-marshaling the decoded struct would turn an
+// omitted publication_year into 0, which is optional but would fail
+// the schema's minimum check.
+func ValidateBook(body []byte) *Problem {
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(body))
+	if err != nil {
+		return &Problem{Title: "Invalid input", Status: http.StatusBadRequest, Detail: err.Error()}
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	fieldErrors := make([]FieldError, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		fieldErrors = append(fieldErrors, FieldError{Field: e.Field(), Message: e.Description()})
+	}
+	return &Problem{
+		Type:   "about:blank",
+		Title:  "Validation failed",
+		Status: http.StatusUnprocessableEntity,
+		Detail: "One or more fields failed validation.",
+		Errors: fieldErrors,
+	}
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			name:    "title and author only, no publication_year",
+			body:    `{"title":"NoYear","author":"Someone"}`,
+			wantErr: false,
+		},
+		{
+			name:    "fully populated book",
+			body:    `{"title":"Dune","author":"Frank Herbert","publication_year":1965,"description":"Desert planet"}`,
+			wantErr: false,
+		},
+		{
+			name:    "missing title",
+			body:    `{"author":"Someone"}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing author",
+			body:    `{"title":"Untitled"}`,
+			wantErr: true,
+		},
+		{
+			name:    "publication_year below minimum",
+			body:    `{"title":"Old","author":"Someone","publication_year":999}`,
+			wantErr: true,
+		},
+		{
+			name:    "title too long",
+			body:    `{"title":"` + strings.Repeat("a", 501) + `","author":"Someone"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := ValidateBook([]byte(tt.body))
+			if tt.wantErr && problem == nil {
+				t.Fatalf("ValidateBook(%s) = nil, want a validation problem", tt.body)
+			}
+			if !tt.wantErr && problem != nil {
+				t.Fatalf("ValidateBook(%s) = %+v, want nil", tt.body, problem)
+			}
+		})
+	}
+}